@@ -0,0 +1,45 @@
+package multihash
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/crypto/blake2s"
+)
+
+func TestSumBlake2s256(t *testing.T) {
+	data := []byte("blake2s-256 reference check")
+
+	want, err := blake2s.New256(nil)
+	if err != nil {
+		t.Fatalf("blake2s.New256 failed: %v", err)
+	}
+	want.Write(data)
+
+	mh, err := Sum(data, BLAKE2S_MAX, -1)
+	if err != nil {
+		t.Fatalf("Sum failed: %v", err)
+	}
+
+	dm, err := Decode(mh)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if !bytes.Equal(dm.Digest, want.Sum(nil)) {
+		t.Errorf("blake2s-256 digest mismatch: expected %x, got %x", want.Sum(nil), dm.Digest)
+	}
+}
+
+func TestSumBlake2sUnsupportedSizesAreRejected(t *testing.T) {
+	// Only blake2s-256 has a correct unkeyed implementation in
+	// golang.org/x/crypto/blake2s; every other blake2s-* code,
+	// including blake2s-128 (which the library only offers as a keyed
+	// MAC), must fail loudly instead of silently returning a truncated
+	// or mis-keyed digest.
+	for _, code := range []int{BLAKE2S_MIN, BLAKE2S_MIN + 1, BLAKE2S_MIN + 15, BLAKE2S_MAX - 1} {
+		if _, err := Sum([]byte("x"), code, -1); err != ErrSumNotSupported {
+			t.Errorf("code %#x: expected ErrSumNotSupported, got %v", code, err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package multihash
+
+import "fmt"
+
+// InvalidMultihashError wraps the underlying cause of a failed Cast,
+// Decode, FromHexString, or FromB58String call, following the same
+// pattern go-cid uses for ErrInvalidCid. Callers that want to catch any
+// malformed-input failure without enumerating every sentinel
+// (ErrTooShort, ErrUnknownCode, ErrInconsistentLen, ...) can write
+// errors.Is(err, InvalidMultihashError{}) once instead.
+//
+// It is a distinct type from the pre-existing ErrInvalidMultihash
+// sentinel var so that old code comparing against
+// multihash.ErrInvalidMultihash keeps compiling and working.
+type InvalidMultihashError struct {
+	Err error
+}
+
+func (e InvalidMultihashError) Error() string {
+	return fmt.Sprintf("invalid multihash: %s", e.Err)
+}
+
+// Unwrap returns the underlying cause so errors.Is/errors.As can reach the
+// wrapped sentinel.
+func (e InvalidMultihashError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is also an InvalidMultihashError, so
+// errors.Is(err, InvalidMultihashError{}) matches regardless of the
+// wrapped cause.
+func (e InvalidMultihashError) Is(target error) bool {
+	_, ok := target.(InvalidMultihashError)
+	return ok
+}
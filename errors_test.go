@@ -0,0 +1,45 @@
+package multihash
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInvalidMultihashErrorWrapsSentinels(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+	}{
+		{"too short", func() error { _, err := Decode([]byte{0x11, 0x02}); return err }()},
+		{"bad hex", func() error { _, err := FromHexString("zz"); return err }()},
+		{"bad base58", func() error { _, err := FromB58String(""); return err }()},
+	}
+
+	for _, c := range cases {
+		if c.err == nil {
+			t.Errorf("%s: expected an error", c.name)
+			continue
+		}
+		if !errors.Is(c.err, InvalidMultihashError{}) {
+			t.Errorf("%s: expected errors.Is to match InvalidMultihashError, got %v", c.name, c.err)
+		}
+	}
+}
+
+func TestInvalidMultihashErrorUnwrapsToSentinel(t *testing.T) {
+	_, err := Decode([]byte{0x11, 0x02})
+	if !errors.Is(err, ErrTooShort) {
+		t.Errorf("expected errors.Is to reach ErrTooShort, got %v", err)
+	}
+}
+
+// TestFromB58StringPreservesOldSentinel guards the back-compat guarantee
+// this type was built for: code written before InvalidMultihashError
+// existed, comparing against the original multihash.ErrInvalidMultihash
+// sentinel via errors.Is, still works.
+func TestFromB58StringPreservesOldSentinel(t *testing.T) {
+	_, err := FromB58String("")
+	if !errors.Is(err, ErrInvalidMultihash) {
+		t.Errorf("expected errors.Is to reach the original ErrInvalidMultihash sentinel, got %v", err)
+	}
+}
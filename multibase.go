@@ -0,0 +1,23 @@
+package multihash
+
+import (
+	"github.com/multiformats/go-multibase"
+)
+
+// StringOfBase encodes the Multihash as a self-describing multibase
+// string using the given base, so the result can be emitted in whatever
+// encoding the caller's tooling (e.g. CIDv1) expects.
+func (m Multihash) StringOfBase(base multibase.Encoding) (string, error) {
+	return multibase.Encode(base, []byte(m))
+}
+
+// FromString parses a multibase-encoded string produced by StringOfBase
+// (or any other multibase encoder) back into a Multihash.
+func FromString(s string) (Multihash, error) {
+	_, data, err := multibase.Decode(s)
+	if err != nil {
+		return Multihash{}, InvalidMultihashError{Err: err}
+	}
+
+	return Cast(data)
+}
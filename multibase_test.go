@@ -0,0 +1,33 @@
+package multihash
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/multiformats/go-multibase"
+)
+
+func TestStringOfBaseRoundTrip(t *testing.T) {
+	digest := []byte{0x01, 0x02, 0x03, 0x04}
+	encoded, err := Encode(digest, SHA2_256)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	mh := Multihash(encoded)
+
+	for _, base := range []multibase.Encoding{multibase.Base32, multibase.Base64url, multibase.Base16Upper} {
+		s, err := mh.StringOfBase(base)
+		if err != nil {
+			t.Fatalf("StringOfBase(%d) failed: %v", base, err)
+		}
+
+		out, err := FromString(s)
+		if err != nil {
+			t.Fatalf("FromString(%q) failed: %v", s, err)
+		}
+
+		if !bytes.Equal([]byte(out), []byte(mh)) {
+			t.Errorf("round trip mismatch for base %d: expected %x, got %x", base, mh, out)
+		}
+	}
+}
@@ -2,18 +2,17 @@ package multihash
 
 import (
 	"encoding/binary"
-	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 
-	b58 "github.com/jbenet/go-base58"
+	"github.com/multiformats/go-multibase"
 )
 
 // errors
 var (
 	ErrUnknownCode      = errors.New("unknown multihash code")
 	ErrTooShort         = errors.New("multihash too short. must be > 3 bytes")
-	ErrTooLong          = errors.New("multihash too long. must be < 129 bytes")
 	ErrLenNotSupported  = errors.New("multihash does not yet support digests longer than 127 bytes")
 	ErrInvalidMultihash = errors.New("input isn't valid multihash")
 )
@@ -49,7 +48,7 @@ func init() {
 		name := fmt.Sprintf("blake2b-%d", n*8)
 		Names[name] = c
 		Codes[c] = name
-		DefaultLengths[c] = n
+		DefaultLengths[c] = int(n)
 	}
 
 	// Add blake2s (32 codes)
@@ -58,12 +57,12 @@ func init() {
 		name := fmt.Sprintf("blake2s-%d", n*8)
 		Names[name] = c
 		Codes[c] = name
-		DefaultLengths[c] = n
+		DefaultLengths[c] = int(n)
 	}
 }
 
 // Names maps the name of a hash to the code
-var Names = map[string]int{
+var Names = map[string]uint64{
 	"sha1":         SHA1,
 	"sha2-256":     SHA2_256,
 	"sha2-512":     SHA2_512,
@@ -72,7 +71,7 @@ var Names = map[string]int{
 }
 
 // Codes maps a hash code to it's name
-var Codes = map[int]string{
+var Codes = map[uint64]string{
 	SHA1:         "sha1",
 	SHA2_256:     "sha2-256",
 	SHA2_512:     "sha2-512",
@@ -81,7 +80,7 @@ var Codes = map[int]string{
 }
 
 // DefaultLengths maps a hash code to it's default length
-var DefaultLengths = map[int]int{
+var DefaultLengths = map[uint64]int{
 	SHA1:         20,
 	SHA2_256:     32,
 	SHA2_512:     64,
@@ -90,16 +89,24 @@ var DefaultLengths = map[int]int{
 }
 
 type DecodedMultihash struct {
-	Code   int
+	Code   uint64
 	Name   string
-	Length int
+	Length uint64
 	Digest []byte
 }
 
 type Multihash []byte
 
+// multibasePrefix is the leading character multibase.Encode prepends to
+// identify the base in use; HexString/B58String strip it back off so
+// their output keeps its original, pre-multibase format.
+func multibasePrefix(base multibase.Encoding) string {
+	return string(rune(base))
+}
+
 func (m *Multihash) HexString() string {
-	return hex.EncodeToString([]byte(*m))
+	s, _ := Multihash(*m).StringOfBase(multibase.Base16)
+	return strings.TrimPrefix(s, multibasePrefix(multibase.Base16))
 }
 
 func (m *Multihash) String() string {
@@ -107,31 +114,28 @@ func (m *Multihash) String() string {
 }
 
 func FromHexString(s string) (Multihash, error) {
-	b, err := hex.DecodeString(s)
-	if err != nil {
-		return Multihash{}, err
-	}
-
-	return Cast(b)
+	return FromString(multibasePrefix(multibase.Base16) + s)
 }
 
 func (m Multihash) B58String() string {
-	return b58.Encode([]byte(m))
+	s, _ := m.StringOfBase(multibase.Base58BTC)
+	return strings.TrimPrefix(s, multibasePrefix(multibase.Base58BTC))
 }
 
-func FromB58String(s string) (m Multihash, err error) {
-	// panic handler, in case we try accessing bytes incorrectly.
-	defer func() {
-		if e := recover(); e != nil {
-			m = Multihash{}
-			err = e.(error)
-		}
-	}()
+func FromB58String(s string) (Multihash, error) {
+	// Preserved from the pre-multibase implementation: an empty (or
+	// empty-decoding) digest is reported as ErrInvalidMultihash rather
+	// than falling through to Cast's ErrTooShort.
+	if s == "" {
+		return Multihash{}, InvalidMultihashError{Err: ErrInvalidMultihash}
+	}
 
-	//b58 smells like it can panic...
-	b := b58.Decode(s)
+	_, b, err := multibase.Decode(multibasePrefix(multibase.Base58BTC) + s)
+	if err != nil {
+		return Multihash{}, InvalidMultihashError{Err: err}
+	}
 	if len(b) == 0 {
-		return Multihash{}, ErrInvalidMultihash
+		return Multihash{}, InvalidMultihashError{Err: ErrInvalidMultihash}
 	}
 
 	return Cast(b)
@@ -144,32 +148,42 @@ func Cast(buf []byte) (Multihash, error) {
 	}
 
 	if !ValidCode(dm.Code) {
-		return Multihash{}, ErrUnknownCode
+		return Multihash{}, InvalidMultihashError{Err: ErrUnknownCode}
 	}
 
 	return Multihash(buf), nil
 }
 
-// Decode a hash from the given Multihash.
+// Decode a hash from the given Multihash. The code and length fields are
+// read as unsigned varints per the multiformats spec, so codes and
+// lengths are no longer limited to a single byte each.
 func Decode(buf []byte) (*DecodedMultihash, error) {
 
 	if len(buf) < 3 {
-		return nil, ErrTooShort
+		return nil, InvalidMultihashError{Err: ErrTooShort}
 	}
 
-	if len(buf) > 129 {
-		return nil, ErrTooLong
+	code, n := binary.Uvarint(buf)
+	if n <= 0 {
+		return nil, InvalidMultihashError{Err: ErrTooShort}
 	}
+	buf = buf[n:]
+
+	length, n := binary.Uvarint(buf)
+	if n <= 0 {
+		return nil, InvalidMultihashError{Err: ErrTooShort}
+	}
+	buf = buf[n:]
 
 	dm := &DecodedMultihash{
-		Code:   int(uint8(buf[0])),
-		Name:   Codes[int(uint8(buf[0]))],
-		Length: int(uint8(buf[1])),
-		Digest: buf[2:],
+		Code:   code,
+		Name:   Codes[code],
+		Length: length,
+		Digest: buf,
 	}
 
-	if len(dm.Digest) != dm.Length {
-		return nil, ErrInconsistentLen{dm}
+	if uint64(len(dm.Digest)) != dm.Length {
+		return nil, InvalidMultihashError{Err: ErrInconsistentLen{dm}}
 	}
 
 	return dm, nil
@@ -177,20 +191,34 @@ func Decode(buf []byte) (*DecodedMultihash, error) {
 
 // Encode a hash digest along with the specified function code.
 // Note: the length is derived from the length of the digest itself.
-func Encode(buf []byte, code int) ([]byte, error) {
+// The code and length are written as unsigned varints, matching Decode.
+func Encode(buf []byte, code uint64) ([]byte, error) {
 
 	if !ValidCode(code) {
 		return nil, ErrUnknownCode
 	}
 
-	if len(buf) > 127 {
-		return nil, ErrLenNotSupported
+	if code < 0x80 && len(buf) < 0x80 {
+		return encodeSmall(buf, code), nil
 	}
 
-	pre := make([]byte, 2)
-	pre[0] = byte(uint8(code))
-	pre[1] = byte(uint8(len(buf)))
-	return append(pre, buf...), nil
+	pre := make([]byte, binary.MaxVarintLen64*2)
+	n := binary.PutUvarint(pre, code)
+	n += binary.PutUvarint(pre[n:], uint64(len(buf)))
+	return append(pre[:n], buf...), nil
+}
+
+// encodeSmall is the back-compat fast path for the pre-varint multihash
+// layout: a single byte each for code and length. It is byte-for-byte
+// identical to the general varint path whenever both fit in one byte
+// (< 0x80, since a one-byte uvarint and a fixed byte are the same
+// thing), so every multihash produced before the varint migration still
+// decodes and re-encodes exactly as it did before.
+func encodeSmall(buf []byte, code uint64) []byte {
+	pre := make([]byte, 2, 2+len(buf))
+	pre[0] = byte(code)
+	pre[1] = byte(len(buf))
+	return append(pre, buf...)
 }
 
 func EncodeName(buf []byte, name string) ([]byte, error) {
@@ -198,7 +226,7 @@ func EncodeName(buf []byte, name string) ([]byte, error) {
 }
 
 // ValidCode checks whether a multihash code is valid.
-func ValidCode(code int) bool {
+func ValidCode(code uint64) bool {
 	if AppCode(code) {
 		return true
 	}
@@ -211,6 +239,6 @@ func ValidCode(code int) bool {
 }
 
 // AppCode checks whether a multihash code is part of the App range.
-func AppCode(code int) bool {
-	return code >= 0 && code < 0x10
+func AppCode(code uint64) bool {
+	return code < 0x10
 }
@@ -0,0 +1,127 @@
+package multihash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncodeDecodeRoundTripBlake2b512(t *testing.T) {
+	code := uint64(BLAKE2B_MIN) + 63 // blake2b-512
+	digest := make([]byte, 64)
+	for i := range digest {
+		digest[i] = byte(i)
+	}
+
+	encoded, err := Encode(digest, code)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	dm, err := Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if dm.Code != code {
+		t.Errorf("expected code %d, got %d", code, dm.Code)
+	}
+	if dm.Name != "blake2b-512" {
+		t.Errorf("expected name blake2b-512, got %q", dm.Name)
+	}
+	if !bytes.Equal(dm.Digest, digest) {
+		t.Errorf("digest mismatch: expected %x, got %x", digest, dm.Digest)
+	}
+}
+
+func TestEncodeDecodeRoundTripLargeCode(t *testing.T) {
+	// A synthetic code well beyond the old single-byte (0xFF) ceiling,
+	// exercising the multi-byte varint path.
+	const largeCode = uint64(0x123456789)
+	Codes[largeCode] = "synthetic-large-code"
+	DefaultLengths[largeCode] = 4
+	defer func() {
+		delete(Codes, largeCode)
+		delete(DefaultLengths, largeCode)
+	}()
+
+	digest := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	encoded, err := Encode(digest, largeCode)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	dm, err := Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if dm.Code != largeCode {
+		t.Errorf("expected code %d, got %d", largeCode, dm.Code)
+	}
+	if !bytes.Equal(dm.Digest, digest) {
+		t.Errorf("digest mismatch: expected %x, got %x", digest, dm.Digest)
+	}
+}
+
+func TestEncodeSmallCodeMatchesPreVarintLayout(t *testing.T) {
+	digest := []byte{0xaa, 0xbb, 0xcc, 0xdd}
+
+	encoded, err := Encode(digest, SHA2_256)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	// The pre-varint layout was a single byte each for code and length.
+	want := append([]byte{byte(SHA2_256), byte(len(digest))}, digest...)
+	if !bytes.Equal(encoded, want) {
+		t.Errorf("expected pre-varint layout %x, got %x", want, encoded)
+	}
+
+	dm, err := Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if dm.Code != SHA2_256 || !bytes.Equal(dm.Digest, digest) {
+		t.Errorf("round trip mismatch: got code %d digest %x", dm.Code, dm.Digest)
+	}
+}
+
+func TestHexStringKeepsItsPreMultibaseFormat(t *testing.T) {
+	mh, err := Encode([]byte{0x01, 0x02, 0x03}, SHA2_256)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	mhVal := Multihash(mh)
+	s := mhVal.HexString()
+	if s != hex.EncodeToString(mh) {
+		t.Errorf("expected plain hex %q, got %q", hex.EncodeToString(mh), s)
+	}
+
+	out, err := FromHexString(s)
+	if err != nil {
+		t.Fatalf("FromHexString failed: %v", err)
+	}
+	if !bytes.Equal(out, mh) {
+		t.Errorf("round trip mismatch: expected %x, got %x", mh, out)
+	}
+}
+
+func TestB58StringRoundTrip(t *testing.T) {
+	mh, err := Encode([]byte{0x01, 0x02, 0x03}, SHA2_256)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	s := Multihash(mh).B58String()
+
+	out, err := FromB58String(s)
+	if err != nil {
+		t.Fatalf("FromB58String failed: %v", err)
+	}
+	if !bytes.Equal(out, mh) {
+		t.Errorf("round trip mismatch: expected %x, got %x", mh, out)
+	}
+}
@@ -0,0 +1,59 @@
+package multihash
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// byteReader adapts an io.Reader to io.ByteReader one byte at a time,
+// without buffering ahead, so a Reader built on top of it never
+// consumes bytes that belong to the digest it exposes.
+type byteReader struct {
+	r io.Reader
+}
+
+func (br *byteReader) ReadByte() (byte, error) {
+	var buf [1]byte
+	_, err := io.ReadFull(br.r, buf[:])
+	return buf[0], err
+}
+
+// Reader consumes the varint-encoded code+length header off an
+// io.Reader and exposes the digest that follows through Read, so
+// callers don't have to buffer an entire block just to learn its
+// multihash prefix.
+type Reader struct {
+	Code   uint64
+	Length uint64
+
+	r io.Reader
+}
+
+// NewReader reads the code and length header off r and returns a
+// Reader whose Read calls stream the Length digest bytes that follow.
+func NewReader(r io.Reader) (*Reader, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = &byteReader{r}
+	}
+
+	code, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, InvalidMultihashError{Err: err}
+	}
+
+	length, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, InvalidMultihashError{Err: err}
+	}
+
+	return &Reader{
+		Code:   code,
+		Length: length,
+		r:      io.LimitReader(r, int64(length)),
+	}, nil
+}
+
+func (mr *Reader) Read(p []byte) (int, error) {
+	return mr.r.Read(p)
+}
@@ -0,0 +1,35 @@
+package multihash
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestSumDblSha256(t *testing.T) {
+	data := []byte("the quick brown fox jumps over the lazy dog")
+
+	first := sha256.Sum256(data)
+	want := sha256.Sum256(first[:])
+
+	mh, err := Sum(data, DBL_SHA2_256, -1)
+	if err != nil {
+		t.Fatalf("Sum failed: %v", err)
+	}
+
+	dm, err := Decode(mh)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if !bytes.Equal(dm.Digest, want[:]) {
+		t.Errorf("expected sha256(sha256(data)) = %x, got %x", want, dm.Digest)
+	}
+}
+
+func TestSumRejectsNegativeLengthsOtherThanMinusOne(t *testing.T) {
+	_, err := Sum([]byte("x"), SHA2_256, -5)
+	if err != ErrLenNotSupported {
+		t.Errorf("expected ErrLenNotSupported, got %v", err)
+	}
+}
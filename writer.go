@@ -0,0 +1,65 @@
+package multihash
+
+import (
+	"hash"
+	"io"
+)
+
+// Writer streams data through a hash function registered via Register
+// and, once Close is called, exposes the resulting encoded Multihash —
+// so content-addressed pipelines (CAR files, block stores) can hash
+// large objects without buffering them whole before calling Sum.
+type Writer struct {
+	w      io.Writer
+	code   int
+	h      hash.Hash
+	length int
+	mh     Multihash
+}
+
+// NewWriter wraps w with the hash function registered for code. Writes
+// are fed into the hash as they arrive; Close finalizes the digest,
+// encodes it as a Multihash using DefaultLengths[code], and writes the
+// encoded bytes to w.
+func NewWriter(w io.Writer, code int) (io.WriteCloser, error) {
+	factory, ok := functionTable[uint64(code)]
+	if !ok {
+		return nil, ErrSumNotSupported
+	}
+
+	length, ok := DefaultLengths[uint64(code)]
+	if !ok {
+		return nil, ErrSumNotSupported
+	}
+
+	return &Writer{w: w, code: code, h: factory(), length: length}, nil
+}
+
+func (mw *Writer) Write(p []byte) (int, error) {
+	return mw.h.Write(p)
+}
+
+// Close finalizes the digest and writes the encoded Multihash to the
+// wrapped io.Writer. The result is also available from Multihash
+// afterwards.
+func (mw *Writer) Close() error {
+	digest := mw.h.Sum(nil)
+	if mw.length > len(digest) {
+		return ErrLenNotSupported
+	}
+
+	mh, err := Encode(digest[:mw.length], uint64(mw.code))
+	if err != nil {
+		return err
+	}
+	mw.mh = mh
+
+	_, err = mw.w.Write(mh)
+	return err
+}
+
+// Multihash returns the Multihash produced by Close. It is only valid
+// after Close has returned successfully.
+func (mw *Writer) Multihash() Multihash {
+	return mw.mh
+}
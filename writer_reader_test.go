@@ -0,0 +1,53 @@
+package multihash
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestWriterReaderRoundTrip(t *testing.T) {
+	payload := []byte("streamed content for a multihash writer/reader round trip")
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, SHA2_256)
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	if _, err := w.Write(payload); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	want, err := Sum(payload, SHA2_256, -1)
+	if err != nil {
+		t.Fatalf("Sum failed: %v", err)
+	}
+	if !bytes.Equal(w.(*Writer).Multihash(), want) {
+		t.Fatalf("Writer multihash mismatch: expected %x, got %x", want, w.(*Writer).Multihash())
+	}
+
+	r, err := NewReader(&buf)
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+
+	digest, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading digest failed: %v", err)
+	}
+
+	wantDM, err := Decode(want)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if r.Code != wantDM.Code {
+		t.Errorf("expected code %d, got %d", wantDM.Code, r.Code)
+	}
+	if !bytes.Equal(digest, wantDM.Digest) {
+		t.Errorf("digest mismatch: expected %x, got %x", wantDM.Digest, digest)
+	}
+}